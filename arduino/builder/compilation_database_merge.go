@@ -0,0 +1,107 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2020 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package builder
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// Merge folds other's entries into db, so a workspace-level
+// compile_commands.json can cover every sketch under a folder instead of
+// just the one that produced db. Entries are deduplicated by file+directory,
+// with other's entry winning on conflict since it reflects the most recently
+// compiled sketch.
+func (db *CompilationDatabase) Merge(other *CompilationDatabase) {
+	db.lock.Lock()
+	defer db.lock.Unlock()
+	other.lock.Lock()
+	defer other.lock.Unlock()
+
+	byKey := make(map[string]int, len(db.Contents))
+	for i, entry := range db.Contents {
+		byKey[compilationDatabaseKey(entry)] = i
+	}
+	for _, entry := range other.Contents {
+		key := compilationDatabaseKey(entry)
+		if i, exists := byKey[key]; exists {
+			db.Contents[i] = entry
+			continue
+		}
+		byKey[key] = len(db.Contents)
+		db.Contents = append(db.Contents, entry)
+	}
+}
+
+func compilationDatabaseKey(entry CompileCommand) string {
+	return entry.Directory + "\x00" + entry.File
+}
+
+// LoadCompilationDatabase reads a compile_commands.json previously written by
+// SaveAtomic. A missing file is not an error: it just yields an empty
+// database, so callers merging into a path that doesn't exist yet don't need
+// a separate existence check.
+func LoadCompilationDatabase(path string) (*CompilationDatabase, error) {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return NewCompilationDatabase(), nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading %s: %w", path, err)
+	}
+
+	var contents []CompileCommand
+	if err := json.Unmarshal(data, &contents); err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", path, err)
+	}
+	db := NewCompilationDatabase()
+	db.Contents = contents
+	return db, nil
+}
+
+// SaveAtomic writes db as indented JSON to path via a tempfile in the same
+// directory followed by a rename, so an editor/clangd watching path never
+// observes a partially-written file.
+func (db *CompilationDatabase) SaveAtomic(path string) error {
+	db.lock.Lock()
+	data, err := json.MarshalIndent(db.Contents, "", "  ")
+	db.lock.Unlock()
+	if err != nil {
+		return fmt.Errorf("encoding compilation database: %w", err)
+	}
+
+	dir := filepath.Dir(path)
+	tmp, err := os.CreateTemp(dir, ".compile_commands-*.json.tmp")
+	if err != nil {
+		return fmt.Errorf("creating temporary file in %s: %w", dir, err)
+	}
+	tmpPath := tmp.Name()
+	defer os.Remove(tmpPath) // no-op once the rename below succeeds
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("writing %s: %w", tmpPath, err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("closing %s: %w", tmpPath, err)
+	}
+	if err := os.Rename(tmpPath, path); err != nil {
+		return fmt.Errorf("renaming %s to %s: %w", tmpPath, path, err)
+	}
+	return nil
+}
@@ -0,0 +1,68 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2020 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package builder
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompilationDatabaseMerge(t *testing.T) {
+	db := NewCompilationDatabase()
+	db.Contents = []CompileCommand{
+		{Directory: "/sketch", File: "sketch.ino.cpp"},
+	}
+
+	other := NewCompilationDatabase()
+	other.Contents = []CompileCommand{
+		{Directory: "/sketch", File: "sketch.ino.cpp", Command: "updated"},
+		{Directory: "/sketch", File: "lib.cpp"},
+	}
+
+	db.Merge(other)
+
+	require.Len(t, db.Contents, 2)
+	byFile := map[string]CompileCommand{}
+	for _, entry := range db.Contents {
+		byFile[entry.File] = entry
+	}
+	require.Equal(t, "updated", byFile["sketch.ino.cpp"].Command)
+	require.Contains(t, byFile, "lib.cpp")
+}
+
+func TestCompilationDatabaseSaveAndLoadAtomic(t *testing.T) {
+	db := NewCompilationDatabase()
+	db.Contents = []CompileCommand{
+		{Directory: "/sketch", File: "sketch.ino.cpp"},
+	}
+
+	path := filepath.Join(t.TempDir(), "compile_commands.json")
+	require.NoError(t, db.SaveAtomic(path))
+
+	loaded, err := LoadCompilationDatabase(path)
+	require.NoError(t, err)
+	require.Equal(t, db.Contents, loaded.Contents)
+}
+
+func TestLoadCompilationDatabaseMissingFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "does-not-exist.json")
+
+	loaded, err := LoadCompilationDatabase(path)
+	require.NoError(t, err)
+	require.Empty(t, loaded.Contents)
+}
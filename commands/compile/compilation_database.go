@@ -0,0 +1,42 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2020 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package compile
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/arduino/arduino-cli/arduino/builder"
+	rpc "github.com/arduino/arduino-cli/rpc/cc/arduino/cli/commands/v1"
+)
+
+// OnlyUpdateCompilationDatabase runs req's sketch through the compile
+// pipeline with Context.OnlyUpdateCompilationDatabase set, so no objects are
+// actually produced, and returns the resulting CompilationDatabase for the
+// caller to merge with others (see `arduino-cli workspace index`).
+func OnlyUpdateCompilationDatabase(ctx context.Context, req *rpc.CompileRequest) (*builder.CompilationDatabase, error) {
+	buildCtx, err := NewContext(req)
+	if err != nil {
+		return nil, fmt.Errorf("preparing build context for %s: %w", req.GetSketchPath(), err)
+	}
+	buildCtx.OnlyUpdateCompilationDatabase = true
+	buildCtx.CompilationDatabase = builder.NewCompilationDatabase()
+
+	if err := RunBuild(buildCtx); err != nil {
+		return nil, fmt.Errorf("compiling %s: %w", req.GetSketchPath(), err)
+	}
+	return buildCtx.CompilationDatabase, nil
+}
@@ -0,0 +1,278 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2020 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package debug
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	rpc "github.com/arduino/arduino-cli/rpc/cc/arduino/cli/commands/v1"
+)
+
+// dapMessage is the common envelope shared by all Debug Adapter Protocol
+// messages (requests, responses and events), framed on the wire as
+// `Content-Length: <n>\r\n\r\n<json>`.
+type dapMessage struct {
+	Seq  int    `json:"seq"`
+	Type string `json:"type"`
+}
+
+type dapRequest struct {
+	dapMessage
+	Command   string          `json:"command"`
+	Arguments json.RawMessage `json:"arguments,omitempty"`
+}
+
+type dapResponse struct {
+	dapMessage
+	RequestSeq int         `json:"request_seq"`
+	Success    bool        `json:"success"`
+	Command    string      `json:"command"`
+	Message    string      `json:"message,omitempty"`
+	Body       interface{} `json:"body,omitempty"`
+}
+
+type dapEvent struct {
+	dapMessage
+	Event string      `json:"event"`
+	Body  interface{} `json:"body,omitempty"`
+}
+
+// dapServer bridges a DAP client (an editor like VS Code or Neovim) speaking
+// newline-free, Content-Length-framed JSON on stdio, to a gdb process running
+// in MI mode. It's the glue `arduino-cli debug --interpreter dap` spins up
+// instead of passing the user's terminal straight through to gdb.
+type dapServer struct {
+	in  *bufio.Reader
+	out io.Writer
+
+	mi *miSession
+
+	seq        atomic.Int64
+	writeMutex sync.Mutex
+}
+
+// RunDAP starts gdb (or lldb-mi, if the toolchain doesn't ship a gdb) in MI
+// mode against the given debug configuration, and serves the Debug Adapter
+// Protocol on in/out until the client disconnects or ctx is cancelled.
+func RunDAP(ctx context.Context, req *rpc.GetDebugConfigRequest, in io.Reader, out io.Writer, interrupt <-chan os.Signal) error {
+	config, err := GetDebugConfig(ctx, req)
+	if err != nil {
+		return err
+	}
+
+	mi, err := startMISession(ctx, config)
+	if err != nil {
+		return err
+	}
+	defer mi.Close()
+
+	server := &dapServer{
+		in:  bufio.NewReader(in),
+		out: out,
+		mi:  mi,
+	}
+
+	go server.forwardMIEvents()
+	go func() {
+		select {
+		case <-interrupt:
+		case <-mi.serverExited:
+		}
+		server.sendEvent("terminated", nil)
+		mi.Close()
+	}()
+
+	return server.serve()
+}
+
+func (s *dapServer) serve() error {
+	for {
+		req, err := s.readRequest()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("reading DAP request: %w", err)
+		}
+
+		if err := s.dispatch(req); err != nil {
+			return err
+		}
+		if req.Command == "disconnect" {
+			return nil
+		}
+	}
+}
+
+// dispatch translates a single DAP request into one or more MI commands and
+// replies with the corresponding DAP response.
+func (s *dapServer) dispatch(req *dapRequest) error {
+	switch req.Command {
+	case "initialize":
+		return s.respond(req, true, map[string]any{
+			"supportsConfigurationDoneRequest": true,
+			"supportsReadMemoryRequest":        true,
+		})
+
+	case "launch":
+		if err := s.mi.Send("-exec-run"); err != nil {
+			return s.respond(req, false, nil)
+		}
+		return s.respond(req, true, nil)
+
+	case "setBreakpoints":
+		var args struct {
+			Source      struct{ Path string } `json:"source"`
+			Breakpoints []struct{ Line int }   `json:"breakpoints"`
+		}
+		if err := json.Unmarshal(req.Arguments, &args); err != nil {
+			return s.respond(req, false, nil)
+		}
+		breakpoints := make([]map[string]any, 0, len(args.Breakpoints))
+		for _, bp := range args.Breakpoints {
+			loc := fmt.Sprintf("%s:%d", args.Source.Path, bp.Line)
+			verified := s.mi.Send(fmt.Sprintf("-break-insert %s", loc)) == nil
+			breakpoints = append(breakpoints, map[string]any{"line": bp.Line, "verified": verified})
+		}
+		return s.respond(req, true, map[string]any{"breakpoints": breakpoints})
+
+	case "stackTrace":
+		return s.respond(req, true, map[string]any{"stackFrames": s.mi.StackTrace()})
+
+	case "variables":
+		return s.respond(req, true, map[string]any{"variables": s.mi.Variables()})
+
+	case "continue":
+		err := s.mi.Send("-exec-continue")
+		return s.respond(req, err == nil, map[string]any{"allThreadsContinued": true})
+
+	case "stepIn":
+		err := s.mi.Send("-exec-step")
+		return s.respond(req, err == nil, nil)
+
+	case "readMemory":
+		var args struct {
+			MemoryReference string `json:"memoryReference"`
+			Count           int    `json:"count"`
+		}
+		if err := json.Unmarshal(req.Arguments, &args); err != nil {
+			return s.respond(req, false, nil)
+		}
+		data, err := s.mi.ReadMemory(args.MemoryReference, args.Count)
+		if err != nil {
+			return s.respond(req, false, nil)
+		}
+		return s.respond(req, true, map[string]any{"address": args.MemoryReference, "data": data})
+
+	case "disconnect":
+		s.mi.Close()
+		return s.respond(req, true, nil)
+
+	default:
+		return s.respond(req, false, map[string]any{"error": "unsupported command: " + req.Command})
+	}
+}
+
+// forwardMIEvents relays gdb/MI async records (stopped, running, output) as
+// DAP events for as long as the underlying MI session is alive.
+func (s *dapServer) forwardMIEvents() {
+	for record := range s.mi.Events() {
+		switch record.Class {
+		case "stopped":
+			s.sendEvent("stopped", map[string]any{"reason": record.Reason, "threadId": 1})
+		case "running":
+			s.sendEvent("thread", map[string]any{"reason": "started", "threadId": 1})
+		default:
+			s.sendEvent("output", map[string]any{"category": "console", "output": record.Payload})
+		}
+	}
+	s.sendEvent("terminated", nil)
+}
+
+func (s *dapServer) readRequest() (*dapRequest, error) {
+	length := -1
+	for {
+		line, err := s.in.ReadString('\n')
+		if err != nil {
+			return nil, err
+		}
+		line = strings.TrimRight(line, "\r\n")
+		if line == "" {
+			break
+		}
+		if strings.HasPrefix(line, "Content-Length:") {
+			length, err = strconv.Atoi(strings.TrimSpace(strings.TrimPrefix(line, "Content-Length:")))
+			if err != nil {
+				return nil, fmt.Errorf("invalid Content-Length header: %w", err)
+			}
+		}
+	}
+	if length < 0 {
+		return nil, fmt.Errorf("missing Content-Length header")
+	}
+
+	body := make([]byte, length)
+	if _, err := io.ReadFull(s.in, body); err != nil {
+		return nil, err
+	}
+	req := &dapRequest{}
+	if err := json.Unmarshal(body, req); err != nil {
+		return nil, fmt.Errorf("decoding DAP request: %w", err)
+	}
+	return req, nil
+}
+
+func (s *dapServer) respond(req *dapRequest, success bool, body interface{}) error {
+	return s.write(&dapResponse{
+		dapMessage: dapMessage{Seq: int(s.seq.Add(1)), Type: "response"},
+		RequestSeq: req.Seq,
+		Success:    success,
+		Command:    req.Command,
+		Body:       body,
+	})
+}
+
+func (s *dapServer) sendEvent(event string, body interface{}) {
+	_ = s.write(&dapEvent{
+		dapMessage: dapMessage{Seq: int(s.seq.Add(1)), Type: "event"},
+		Event:      event,
+		Body:       body,
+	})
+}
+
+func (s *dapServer) write(msg interface{}) error {
+	data, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+
+	s.writeMutex.Lock()
+	defer s.writeMutex.Unlock()
+	if _, err := fmt.Fprintf(s.out, "Content-Length: %d\r\n\r\n", len(data)); err != nil {
+		return err
+	}
+	_, err = s.out.Write(data)
+	return err
+}
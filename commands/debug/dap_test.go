@@ -0,0 +1,101 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2020 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package debug
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func writeDAPMessage(t *testing.T, buf *bytes.Buffer, msg any) {
+	t.Helper()
+	data, err := json.Marshal(msg)
+	require.NoError(t, err)
+	fmt.Fprintf(buf, "Content-Length: %d\r\n\r\n", len(data))
+	buf.Write(data)
+}
+
+func TestDAPReadRequest(t *testing.T) {
+	var in bytes.Buffer
+	writeDAPMessage(t, &in, dapRequest{
+		dapMessage: dapMessage{Seq: 1, Type: "request"},
+		Command:    "initialize",
+	})
+
+	server := &dapServer{in: bufio.NewReader(&in)}
+
+	req, err := server.readRequest()
+	require.NoError(t, err)
+	require.Equal(t, "initialize", req.Command)
+	require.Equal(t, 1, req.Seq)
+}
+
+func TestDAPReadRequestMissingContentLength(t *testing.T) {
+	in := bytes.NewBufferString("\r\n{}")
+	server := &dapServer{in: bufio.NewReader(in)}
+
+	_, err := server.readRequest()
+	require.Error(t, err)
+}
+
+func TestDAPDispatchInitialize(t *testing.T) {
+	var out bytes.Buffer
+	server := &dapServer{out: &out}
+
+	err := server.dispatch(&dapRequest{
+		dapMessage: dapMessage{Seq: 1, Type: "request"},
+		Command:    "initialize",
+	})
+	require.NoError(t, err)
+
+	resp := readDAPResponse(t, &out)
+	require.True(t, resp.Success)
+	require.Equal(t, "initialize", resp.Command)
+}
+
+func TestDAPDispatchUnsupportedCommand(t *testing.T) {
+	var out bytes.Buffer
+	server := &dapServer{out: &out}
+
+	err := server.dispatch(&dapRequest{
+		dapMessage: dapMessage{Seq: 1, Type: "request"},
+		Command:    "totallyUnknown",
+	})
+	require.NoError(t, err)
+
+	resp := readDAPResponse(t, &out)
+	require.False(t, resp.Success)
+}
+
+func readDAPResponse(t *testing.T, buf *bytes.Buffer) dapResponse {
+	t.Helper()
+	reader := bufio.NewReader(buf)
+	line, err := reader.ReadString('\n')
+	require.NoError(t, err)
+	require.Contains(t, line, "Content-Length:")
+
+	_, err = reader.ReadString('\n') // blank line separating header from body
+	require.NoError(t, err)
+
+	var resp dapResponse
+	require.NoError(t, json.NewDecoder(reader).Decode(&resp))
+	return resp
+}
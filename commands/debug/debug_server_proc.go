@@ -0,0 +1,101 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2020 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package debug
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"os/exec"
+
+	rpc "github.com/arduino/arduino-cli/rpc/cc/arduino/cli/commands/v1"
+)
+
+// localServerPort is the loopback address gdb connects to for the
+// GDB remote-serial-protocol port OpenOCD/J-Link's server process binds by
+// default, before --listen re-exposes it on the address the user asked for.
+const localServerPort = "127.0.0.1:3333"
+
+// startDebugServer launches the server process described by config (its path
+// and server-specific configuration were already resolved by GetDebugConfig)
+// and returns the local address it's listening on for GDB remote
+// connections, along with a channel that's closed when the server process
+// exits on its own (e.g. the probe was unplugged), so the caller can treat
+// that the same as an explicit shutdown request.
+func startDebugServer(ctx context.Context, config *rpc.GetDebugConfigResponse) (string, io.Closer, <-chan struct{}, error) {
+	args, err := serverArgs(config)
+	if err != nil {
+		return "", nil, nil, err
+	}
+	cmd := exec.CommandContext(ctx, config.GetServerPath(), args...)
+	if err := cmd.Start(); err != nil {
+		return "", nil, nil, fmt.Errorf("starting %s: %w", config.GetServer(), err)
+	}
+
+	exited := make(chan struct{})
+	go func() {
+		defer close(exited)
+		cmd.Wait()
+	}()
+
+	return localServerPort, processCloser{cmd}, exited, nil
+}
+
+// processCloser adapts an *exec.Cmd's running process to io.Closer, so the
+// caller can tear the debug server down the same way it would close a
+// network connection.
+type processCloser struct {
+	cmd *exec.Cmd
+}
+
+func (p processCloser) Close() error {
+	if p.cmd.Process == nil {
+		return nil
+	}
+	return p.cmd.Process.Kill()
+}
+
+// serverArgs builds the command-line arguments for the configured debug
+// server. Each server type (openocd, blackmagic, jlink, ...) has its own
+// invocation conventions, already captured in config.ServerConfiguration.
+func serverArgs(config *rpc.GetDebugConfigResponse) ([]string, error) {
+	switch config.GetServer() {
+	case "blackmagic":
+		// The probe's GDB server lives on the device itself; nothing to launch.
+		return nil, nil
+	case "openocd":
+		var openocdConf rpc.DebugOpenOCDServerConfiguration
+		if err := config.GetServerConfiguration().UnmarshalTo(&openocdConf); err != nil {
+			return nil, fmt.Errorf("decoding openocd server configuration: %w", err)
+		}
+		args := []string{"-s", openocdConf.ScriptsDir}
+		for _, script := range openocdConf.Scripts {
+			args = append(args, "-f", script)
+		}
+		return args, nil
+	default:
+		return nil, fmt.Errorf("unsupported debug server %q", config.GetServer())
+	}
+}
+
+// pipeConn forwards bytes between two connections until either side closes.
+func pipeConn(a, b net.Conn) {
+	done := make(chan struct{}, 2)
+	go func() { io.Copy(a, b); done <- struct{}{} }()
+	go func() { io.Copy(b, a); done <- struct{}{} }()
+	<-done
+}
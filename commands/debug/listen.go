@@ -0,0 +1,97 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2020 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package debug
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"os"
+
+	rpc "github.com/arduino/arduino-cli/rpc/cc/arduino/cli/commands/v1"
+)
+
+// ServerOnlySession is a debug server (OpenOCD / Black Magic / J-Link GDB
+// server) started in --listen mode, proxying its GDB remote-serial-protocol
+// port on the caller-requested address. Callers must call Wait to keep the
+// session (and the underlying server process) alive.
+type ServerOnlySession struct {
+	listener     net.Listener
+	server       io.Closer
+	serverExited <-chan struct{}
+}
+
+// DebugServerOnly starts only the debug server described by req and proxies
+// its GDB remote-serial-protocol port on req.ListenAddress, instead of
+// launching an interactive gdb session. The returned response's
+// RemoteEndpoint is ready to print as soon as this returns; the caller must
+// then call session.Wait to actually keep the server alive.
+func DebugServerOnly(ctx context.Context, req *rpc.GetDebugConfigRequest) (*rpc.GetDebugConfigResponse, *ServerOnlySession, error) {
+	config, err := GetDebugConfig(ctx, req)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	serverPort, server, serverExited, err := startDebugServer(ctx, config)
+	if err != nil {
+		return nil, nil, fmt.Errorf("starting debug server: %w", err)
+	}
+
+	listener, err := net.Listen("tcp", req.GetListenAddress())
+	if err != nil {
+		server.Close()
+		return nil, nil, fmt.Errorf("listening on %s: %w", req.GetListenAddress(), err)
+	}
+	go proxyGDBConnections(listener, serverPort)
+
+	config.RemoteEndpoint = listener.Addr().String()
+	return config, &ServerOnlySession{listener: listener, server: server, serverExited: serverExited}, nil
+}
+
+// Wait blocks for the lifetime of the --listen command: either interrupt
+// fires, or the debug server subprocess exits on its own (e.g. the probe was
+// unplugged), whichever happens first. Either way it tears the listener and
+// the server process down before returning.
+func (s *ServerOnlySession) Wait(interrupt <-chan os.Signal) {
+	select {
+	case <-interrupt:
+	case <-s.serverExited:
+	}
+	s.listener.Close()
+	s.server.Close()
+}
+
+// proxyGDBConnections accepts TCP connections on listener and forwards the
+// GDB remote-serial-protocol bytes to/from the debug server's own local
+// port, until the listener is closed.
+func proxyGDBConnections(listener net.Listener, serverAddr string) {
+	for {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		go func() {
+			defer conn.Close()
+			upstream, err := net.Dial("tcp", serverAddr)
+			if err != nil {
+				return
+			}
+			defer upstream.Close()
+			pipeConn(conn, upstream)
+		}()
+	}
+}
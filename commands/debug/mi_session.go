@@ -0,0 +1,324 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2020 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package debug
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"os/exec"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+
+	rpc "github.com/arduino/arduino-cli/rpc/cc/arduino/cli/commands/v1"
+)
+
+// miRecord is a single gdb/MI async or out-of-band record, e.g.
+// `*stopped,reason="breakpoint-hit"`.
+type miRecord struct {
+	Class   string
+	Payload string
+	// Reason is the record's reason="..." field, if any, e.g. "breakpoint-hit"
+	// or "end-stepping-range" on a *stopped record.
+	Reason string
+}
+
+// miResult is the result record gdb sends back for a specific command, e.g.
+// `3^done,stack=[...]` for a command sent as "3-stack-list-frames".
+type miResult struct {
+	Class   string // "done", "error", "running", ...
+	Payload string
+}
+
+// miSession wraps a `gdb --interpreter=mi2` (or `lldb-mi`) child process,
+// used by the DAP adapter as its actual debugging backend. Commands are sent
+// with a numeric token so their result record can be matched back to the
+// caller that issued them, as recommended by the GDB/MI spec.
+type miSession struct {
+	cmd   *exec.Cmd
+	stdin io.WriteCloser
+
+	server       io.Closer
+	serverExited <-chan struct{}
+
+	events chan miRecord
+
+	writeMutex sync.Mutex
+	nextToken  int
+
+	pendingMu sync.Mutex
+	pending   map[int]chan miResult
+}
+
+// startMISession spawns the toolchain's gdb in MI mode (falling back to
+// lldb-mi when the toolchain doesn't ship one) against the executable
+// described by config, launches the configured debug server as a child
+// process managed alongside it, and attaches gdb to it before returning, so
+// callers can start issuing MI commands against the live target right away.
+func startMISession(ctx context.Context, config *rpc.GetDebugConfigResponse) (*miSession, error) {
+	gdbPath := config.GetToolchainPath() + "/" + config.GetToolchainPrefix() + "gdb"
+
+	args := []string{"--interpreter=mi2", "--quiet", config.GetExecutable()}
+	cmd := exec.CommandContext(ctx, gdbPath, args...)
+
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return nil, fmt.Errorf("creating gdb stdin pipe: %w", err)
+	}
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, fmt.Errorf("creating gdb stdout pipe: %w", err)
+	}
+
+	serverPort, server, serverExited, err := startDebugServer(ctx, config)
+	if err != nil {
+		return nil, fmt.Errorf("starting debug server: %w", err)
+	}
+
+	if err := cmd.Start(); err != nil {
+		server.Close()
+		return nil, fmt.Errorf("starting gdb in MI mode: %w", err)
+	}
+
+	session := &miSession{
+		cmd:          cmd,
+		stdin:        stdin,
+		server:       server,
+		serverExited: serverExited,
+		events:       make(chan miRecord, 64),
+		pending:      make(map[int]chan miResult),
+	}
+	go session.readLoop(stdout)
+
+	if err := session.attachTarget(config, serverPort); err != nil {
+		session.Close()
+		return nil, err
+	}
+	return session, nil
+}
+
+// attachTarget issues the GDB/MI commands needed to connect gdb to the debug
+// server started alongside it, so the session is already attached to the
+// live target by the time the DAP client sends its first launch/continue
+// request.
+func (s *miSession) attachTarget(config *rpc.GetDebugConfigResponse, serverPort string) error {
+	var cmds []string
+	if config.GetServer() == "blackmagic" {
+		var bmpConf rpc.DebugBlackMagicProbeServerConfiguration
+		if err := config.GetServerConfiguration().UnmarshalTo(&bmpConf); err != nil {
+			return fmt.Errorf("reading blackmagic server configuration: %w", err)
+		}
+		cmds = blackMagicProbeGDBBootstrap(&bmpConf)
+	} else {
+		cmds = []string{fmt.Sprintf("target extended-remote %s", serverPort)}
+	}
+
+	for _, cmd := range cmds {
+		if _, err := s.sendSync("-interpreter-exec console " + strconv.Quote(cmd)); err != nil {
+			return fmt.Errorf("attaching to target: %w", err)
+		}
+	}
+	return nil
+}
+
+// miResultLine matches a token-prefixed result record, e.g. "3^done,foo=bar".
+var miResultLine = regexp.MustCompile(`^(\d+)\^(\w+)(?:,(.*))?$`)
+
+// miReasonField pulls out a top-level reason="..." field directly, rather
+// than going through miParseTuples: a *stopped record's payload often also
+// carries a nested frame={...} tuple, which miParseTuples("{"+payload+"}", "")
+// would mistake for the outermost (and only) tuple, hiding reason entirely.
+var miReasonField = regexp.MustCompile(`reason="((?:[^"\\]|\\.)*)"`)
+
+func (s *miSession) readLoop(stdout io.Reader) {
+	defer close(s.events)
+	scanner := bufio.NewScanner(stdout)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := scanner.Text()
+
+		if m := miResultLine.FindStringSubmatch(line); m != nil {
+			token, _ := strconv.Atoi(m[1])
+			s.deliverResult(token, miResult{Class: m[2], Payload: m[3]})
+			continue
+		}
+
+		if line == "" || line == "(gdb)" {
+			continue
+		}
+		class, payload, ok := strings.Cut(line, ",")
+		if !ok {
+			class, payload = line, ""
+		}
+		reason := ""
+		if m := miReasonField.FindStringSubmatch(payload); m != nil {
+			reason = m[1]
+		}
+		s.events <- miRecord{Class: strings.TrimLeft(class, "*+="), Payload: payload, Reason: reason}
+	}
+}
+
+func (s *miSession) deliverResult(token int, result miResult) {
+	s.pendingMu.Lock()
+	ch, ok := s.pending[token]
+	if ok {
+		delete(s.pending, token)
+	}
+	s.pendingMu.Unlock()
+	if ok {
+		ch <- result
+	}
+}
+
+// Send writes a single MI command (e.g. "-exec-continue") to gdb's stdin,
+// without waiting for its result record.
+func (s *miSession) Send(command string) error {
+	s.writeMutex.Lock()
+	defer s.writeMutex.Unlock()
+	_, err := fmt.Fprintf(s.stdin, "%s\n", command)
+	return err
+}
+
+// sendSync writes an MI command prefixed with a fresh token and blocks until
+// the matching result record comes back from readLoop.
+func (s *miSession) sendSync(command string) (miResult, error) {
+	s.pendingMu.Lock()
+	token := s.nextToken
+	s.nextToken++
+	ch := make(chan miResult, 1)
+	s.pending[token] = ch
+	s.pendingMu.Unlock()
+
+	s.writeMutex.Lock()
+	_, err := fmt.Fprintf(s.stdin, "%d%s\n", token, command)
+	s.writeMutex.Unlock()
+	if err != nil {
+		s.pendingMu.Lock()
+		delete(s.pending, token)
+		s.pendingMu.Unlock()
+		return miResult{}, err
+	}
+
+	result := <-ch
+	if result.Class == "error" {
+		return result, fmt.Errorf("gdb/MI error running %q: %s", command, result.Payload)
+	}
+	return result, nil
+}
+
+// Events streams MI async/out-of-band records as they arrive from gdb.
+func (s *miSession) Events() <-chan miRecord {
+	return s.events
+}
+
+// StackTrace requests the current call stack via -stack-list-frames and
+// returns it as DAP StackFrame-shaped maps.
+func (s *miSession) StackTrace() []map[string]any {
+	result, err := s.sendSync("-stack-list-frames")
+	if err != nil {
+		return nil
+	}
+	frames := miParseTuples(result.Payload, "frame")
+	stack := make([]map[string]any, 0, len(frames))
+	for _, f := range frames {
+		level, _ := strconv.Atoi(f["level"])
+		line, _ := strconv.Atoi(f["line"])
+		stack = append(stack, map[string]any{
+			"id":     level,
+			"name":   f["func"],
+			"line":   line,
+			"column": 0,
+			"source": map[string]any{"path": f["file"]},
+		})
+	}
+	return stack
+}
+
+// Variables requests the variables in scope for the current frame via
+// -stack-list-variables and returns them as DAP Variable-shaped maps.
+func (s *miSession) Variables() []map[string]any {
+	result, err := s.sendSync("-stack-list-variables --simple-values")
+	if err != nil {
+		return nil
+	}
+	vars := miParseTuples(result.Payload, "")
+	variables := make([]map[string]any, 0, len(vars))
+	for _, v := range vars {
+		variables = append(variables, map[string]any{
+			"name":               v["name"],
+			"value":              v["value"],
+			"type":               v["type"],
+			"variablesReference": 0,
+		})
+	}
+	return variables
+}
+
+// ReadMemory reads count bytes starting at address via
+// -data-read-memory-bytes and returns the hex-encoded contents gdb reports.
+func (s *miSession) ReadMemory(address string, count int) (string, error) {
+	result, err := s.sendSync(fmt.Sprintf("-data-read-memory-bytes %s %d", address, count))
+	if err != nil {
+		return "", err
+	}
+	blocks := miParseTuples(result.Payload, "")
+	if len(blocks) == 0 {
+		return "", fmt.Errorf("no memory returned for %s", address)
+	}
+	return blocks[0]["contents"], nil
+}
+
+// Close terminates the gdb child process and the debug server alongside it.
+func (s *miSession) Close() error {
+	s.stdin.Close()
+	if s.server != nil {
+		s.server.Close()
+	}
+	if s.cmd.Process != nil {
+		return s.cmd.Process.Kill()
+	}
+	return nil
+}
+
+// miField matches a single key="value" pair inside an MI tuple/list.
+var miField = regexp.MustCompile(`(\w+)="((?:[^"\\]|\\.)*)"`)
+
+// miParseTuples extracts the key="value" fields of each `name={...}` (or
+// bare `{...}`, when prefix is empty) tuple found in an MI result payload.
+// This covers the handful of record shapes the DAP adapter needs
+// (stack frames, simple-valued variables, memory blocks) without
+// implementing the full GDB/MI output grammar.
+func miParseTuples(payload, prefix string) []map[string]string {
+	var pattern *regexp.Regexp
+	if prefix != "" {
+		pattern = regexp.MustCompile(prefix + `=\{([^{}]*)\}`)
+	} else {
+		pattern = regexp.MustCompile(`\{([^{}]*)\}`)
+	}
+
+	var tuples []map[string]string
+	for _, m := range pattern.FindAllStringSubmatch(payload, -1) {
+		fields := map[string]string{}
+		for _, f := range miField.FindAllStringSubmatch(m[1], -1) {
+			fields[f[1]] = strings.ReplaceAll(f[2], `\"`, `"`)
+		}
+		tuples = append(tuples, fields)
+	}
+	return tuples
+}
@@ -0,0 +1,39 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2020 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package debug
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMIReasonFieldSurvivesNestedFrameTuple(t *testing.T) {
+	payload := `reason="breakpoint-hit",frame={addr="0x08000214",func="main",file="foo.c",line="68"},thread-id="1"`
+
+	m := miReasonField.FindStringSubmatch(payload)
+	require.NotNil(t, m)
+	require.Equal(t, "breakpoint-hit", m[1])
+}
+
+func TestMIParseTuplesFrame(t *testing.T) {
+	payload := `frame={level="0",func="main",file="foo.c",line="68"}`
+
+	frames := miParseTuples(payload, "frame")
+	require.Len(t, frames, 1)
+	require.Equal(t, "0", frames[0]["level"])
+	require.Equal(t, "main", frames[0]["func"])
+}
@@ -0,0 +1,94 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2020 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package debug
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	rpc "github.com/arduino/arduino-cli/rpc/cc/arduino/cli/commands/v1"
+	properties "github.com/arduino/go-properties-orderedmap"
+	"google.golang.org/protobuf/types/known/anypb"
+)
+
+// defaultBlackMagicProbeGDBPort is the USB CDC-ACM device the probe usually
+// enumerates its GDB server on, used when platform.txt doesn't override it.
+const defaultBlackMagicProbeGDBPort = "/dev/ttyACM0"
+
+// populateServerConfiguration builds the server-specific configuration to embed
+// in a GetDebugConfigResponse, based on the `debug.server.<name>.*` properties
+// set in the board's platform.txt.
+func populateServerConfiguration(serverName string, props *properties.Map) (*anypb.Any, error) {
+	switch serverName {
+	case "openocd":
+		conf, err := anypb.New(&rpc.DebugOpenOCDServerConfiguration{
+			Path:       props.Get("debug.server.openocd.path"),
+			ScriptsDir: props.Get("debug.server.openocd.scripts_dir"),
+			Scripts:    strings.Split(props.Get("debug.server.openocd.scripts"), ","),
+		})
+		if err != nil {
+			return nil, fmt.Errorf("building openocd server configuration: %w", err)
+		}
+		return conf, nil
+
+	case "blackmagic":
+		conf, err := anypb.New(&rpc.DebugBlackMagicProbeServerConfiguration{
+			GdbPort:        props.GetOrDefault("debug.server.blackmagic.gdb_port", defaultBlackMagicProbeGDBPort),
+			ScanCmd:        props.GetOrDefault("debug.server.blackmagic.scan_cmd", "monitor swdp_scan"),
+			AttachTarget:   props.GetOrDefault("debug.server.blackmagic.attach_target", "1"),
+			MonitorPreInit: splitNonEmpty(props.Get("debug.server.blackmagic.monitor_pre_init"), ","),
+		})
+		if err != nil {
+			return nil, fmt.Errorf("building blackmagic server configuration: %w", err)
+		}
+		return conf, nil
+
+	default:
+		return nil, nil
+	}
+}
+
+// blackMagicProbeGDBBootstrap returns the sequence of GDB commands needed to
+// connect to a Black Magic Probe before the user's own gdbinit is sourced:
+// attaching to the probe's GDB serial device, running any monitor commands
+// the platform.txt asked for, scanning for the target and attaching to it.
+// miSession.attachTarget runs these through gdb/MI when server == "blackmagic",
+// ahead of any DAP launch/continue request.
+func blackMagicProbeGDBBootstrap(conf *rpc.DebugBlackMagicProbeServerConfiguration) []string {
+	cmds := []string{fmt.Sprintf("target extended-remote %s", conf.GetGdbPort())}
+	cmds = append(cmds, conf.GetMonitorPreInit()...)
+	cmds = append(cmds, conf.GetScanCmd())
+
+	target, err := strconv.Atoi(conf.GetAttachTarget())
+	if err != nil {
+		target = 1
+	}
+	return append(cmds, fmt.Sprintf("attach %d", target))
+}
+
+func splitNonEmpty(s, sep string) []string {
+	if s == "" {
+		return nil
+	}
+	var res []string
+	for _, part := range strings.Split(s, sep) {
+		if part = strings.TrimSpace(part); part != "" {
+			res = append(res, part)
+		}
+	}
+	return res
+}
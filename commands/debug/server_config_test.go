@@ -0,0 +1,53 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2020 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package debug
+
+import (
+	"testing"
+
+	rpc "github.com/arduino/arduino-cli/rpc/cc/arduino/cli/commands/v1"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBlackMagicProbeGDBBootstrap(t *testing.T) {
+	conf := &rpc.DebugBlackMagicProbeServerConfiguration{
+		GdbPort:        "/dev/ttyACM0",
+		ScanCmd:        "monitor swdp_scan",
+		AttachTarget:   "2",
+		MonitorPreInit: []string{"monitor tpwr enable"},
+	}
+
+	cmds := blackMagicProbeGDBBootstrap(conf)
+
+	require.Equal(t, []string{
+		"target extended-remote /dev/ttyACM0",
+		"monitor tpwr enable",
+		"monitor swdp_scan",
+		"attach 2",
+	}, cmds)
+}
+
+func TestBlackMagicProbeGDBBootstrapDefaultsTargetOnBadInt(t *testing.T) {
+	conf := &rpc.DebugBlackMagicProbeServerConfiguration{
+		GdbPort:      "/dev/ttyACM0",
+		ScanCmd:      "monitor swdp_scan",
+		AttachTarget: "",
+	}
+
+	cmds := blackMagicProbeGDBBootstrap(conf)
+
+	require.Equal(t, "attach 1", cmds[len(cmds)-1])
+}
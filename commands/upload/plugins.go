@@ -0,0 +1,117 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2020 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package upload
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/arduino/arduino-cli/arduino/cores/packagemanager"
+	"github.com/arduino/arduino-cli/legacy/builder/types"
+	paths "github.com/arduino/go-paths-helper"
+	"gopkg.in/yaml.v3"
+)
+
+// uploaderPluginDescriptorFileName is the file a platform drops into its
+// tools/ directory to register an external uploader backend, e.g.
+// tools/blisp/plugin.yaml.
+const uploaderPluginDescriptorFileName = "plugin.yaml"
+
+// uploaderPluginDescriptor is the on-disk (YAML or JSON) shape of an
+// uploader plugin registration.
+type uploaderPluginDescriptor struct {
+	Name              string   `yaml:"name" json:"name"`
+	Tool              string   `yaml:"tool" json:"tool"`
+	Args              []string `yaml:"args" json:"args"`
+	ExpectedExitCodes []int    `yaml:"expected_exit_codes" json:"expected_exit_codes"`
+	ProducesUF2       bool     `yaml:"produces_uf2" json:"produces_uf2"`
+	FlashAddress      uint32   `yaml:"flash_address" json:"flash_address"`
+}
+
+// LoadUploaderPlugins scans every installed platform's tools/ directory for
+// plugin.yaml (or plugin.json) descriptors and returns the resulting
+// registry, keyed by plugin name, ready to be assigned to Context.Uploaders.
+func LoadUploaderPlugins(pm *packagemanager.Explorer) (map[string]*types.UploaderPlugin, error) {
+	registry := map[string]*types.UploaderPlugin{}
+	for _, platform := range pm.InstalledPlatformReleases() {
+		toolsDir := platform.InstallDir.Join("tools")
+		if !toolsDir.IsDir() {
+			continue
+		}
+		dirs, err := toolsDir.ReadDir()
+		if err != nil {
+			return nil, fmt.Errorf("reading %s: %w", toolsDir, err)
+		}
+		for _, dir := range dirs {
+			plugin, err := loadUploaderPluginDescriptor(dir)
+			if err != nil {
+				return nil, err
+			}
+			if plugin != nil {
+				registry[plugin.Name] = plugin
+			}
+		}
+	}
+	return registry, nil
+}
+
+func loadUploaderPluginDescriptor(toolDir *paths.Path) (*types.UploaderPlugin, error) {
+	descriptorPath := toolDir.Join(uploaderPluginDescriptorFileName)
+	if !descriptorPath.Exist() {
+		descriptorPath = toolDir.Join("plugin.json")
+		if !descriptorPath.Exist() {
+			return nil, nil
+		}
+	}
+
+	data, err := descriptorPath.ReadFile()
+	if err != nil {
+		return nil, fmt.Errorf("reading %s: %w", descriptorPath, err)
+	}
+
+	var desc uploaderPluginDescriptor
+	if descriptorPath.Ext() == ".json" {
+		err = json.Unmarshal(data, &desc)
+	} else {
+		err = yaml.Unmarshal(data, &desc)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", descriptorPath, err)
+	}
+
+	return &types.UploaderPlugin{
+		Name:              desc.Name,
+		ToolPath:          toolDir.Join(desc.Tool),
+		ArgsTemplate:      desc.Args,
+		ExpectedExitCodes: desc.ExpectedExitCodes,
+		ProducesUF2:       desc.ProducesUF2,
+		FlashAddress:      desc.FlashAddress,
+	}, nil
+}
+
+// ListUploaderPlugins is the backing implementation of
+// `arduino-cli upload plugins list`.
+func ListUploaderPlugins(pm *packagemanager.Explorer) ([]*types.UploaderPlugin, error) {
+	registry, err := LoadUploaderPlugins(pm)
+	if err != nil {
+		return nil, err
+	}
+	plugins := make([]*types.UploaderPlugin, 0, len(registry))
+	for _, plugin := range registry {
+		plugins = append(plugins, plugin)
+	}
+	return plugins, nil
+}
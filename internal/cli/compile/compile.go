@@ -0,0 +1,126 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2020 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+// Package compile implements `arduino-cli compile`.
+package compile
+
+import (
+	"context"
+	"os"
+
+	"github.com/arduino/arduino-cli/arduino/builder"
+	"github.com/arduino/arduino-cli/commands/compile"
+	"github.com/arduino/arduino-cli/commands/sketch"
+	"github.com/arduino/arduino-cli/internal/cli/arguments"
+	"github.com/arduino/arduino-cli/internal/cli/feedback"
+	"github.com/arduino/arduino-cli/internal/cli/instance"
+	rpc "github.com/arduino/arduino-cli/rpc/cc/arduino/cli/commands/v1"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+var (
+	fqbnArg                 arguments.Fqbn
+	portArgs                arguments.Port
+	watch                   bool
+	upload                  bool
+	verbose                 bool
+	exportBinaries          bool
+	buildProperties         []string
+	libraries               []string
+	warnings                string
+	clean                   bool
+	onlyCompilationDatabase bool
+)
+
+// NewCommand created a new `compile` command.
+func NewCommand() *cobra.Command {
+	compileCommand := &cobra.Command{
+		Use:     "compile",
+		Short:   tr("Compiles Arduino sketches."),
+		Example: "  " + os.Args[0] + " compile -b arduino:avr:uno /home/user/Arduino/MySketch",
+		Args:    cobra.MaximumNArgs(1),
+		Run:     runCompileCommand,
+	}
+
+	fqbnArg.AddToCommand(compileCommand)
+	portArgs.AddToCommand(compileCommand)
+	compileCommand.Flags().BoolVarP(&verbose, "verbose", "v", false, tr("Optional, turns on verbose mode."))
+	compileCommand.Flags().BoolVarP(&exportBinaries, "export-binaries", "e", false, tr("If set built binaries will be exported to the sketch folder."))
+	compileCommand.Flags().StringSliceVar(&buildProperties, "build-properties", []string{}, tr("List of custom build properties separated by commas. Or can be used multiple times for multiple properties."))
+	compileCommand.Flags().StringSliceVar(&libraries, "libraries", []string{}, tr("List of custom libraries folders (as relative or absolute paths) separated by commas. Or can be used multiple times for multiple libraries folders."))
+	compileCommand.Flags().StringVar(&warnings, "warnings", "none", tr("Optional, can be: %s. Defaults to %s.", "none, default, more, all", "none"))
+	compileCommand.Flags().BoolVar(&clean, "clean", false, tr("Optional, cleanup the build folder and do not use any cached build."))
+	compileCommand.Flags().BoolVar(&onlyCompilationDatabase, "only-compilation-database", false, tr("Just produce the compilation database, without actually compiling."))
+	compileCommand.Flags().BoolVar(&watch, "watch", false, tr("Watch the sketch's files and recompile on change, instead of compiling once and exiting."))
+	compileCommand.Flags().BoolVar(&upload, "upload", false, tr("Upload the binary after a successful compile. With %s, upload again after each rebuild.", "--watch"))
+	compileCommand.Flags().StringVar(&compileCommandsOut, "compile-commands-out", "", tr("Merge this sketch's compilation database into the one at the given path, instead of discarding it."))
+
+	return compileCommand
+}
+
+func runCompileCommand(command *cobra.Command, args []string) {
+	inst := instance.CreateAndInit()
+	logrus.Info("Executing `arduino-cli compile`")
+
+	path := ""
+	if len(args) > 0 {
+		path = args[0]
+	}
+
+	sketchPath := arguments.InitSketchPath(path, true)
+	sk, err := sketch.LoadSketch(context.Background(), &rpc.LoadSketchRequest{SketchPath: sketchPath.String()})
+	if err != nil {
+		feedback.FatalError(err, feedback.ErrGeneric)
+	}
+	fqbn, port := arguments.CalculateFQBNAndPort(&portArgs, &fqbnArg, inst, sk.GetDefaultFqbn(), sk.GetDefaultPort(), sk.GetDefaultProtocol())
+	req := &rpc.CompileRequest{
+		Instance:                      inst,
+		Fqbn:                          fqbn,
+		SketchPath:                    sketchPath.String(),
+		Port:                          port,
+		Verbose:                       verbose,
+		ExportBinaries:                exportBinaries,
+		BuildProperties:               buildProperties,
+		Libraries:                     libraries,
+		Warnings:                      warnings,
+		Clean:                         clean,
+		CreateCompilationDatabaseOnly: onlyCompilationDatabase,
+	}
+
+	if watch {
+		runCompileWatchCommand(req, upload)
+		return
+	}
+
+	ctx, err := compile.NewContext(req)
+	if err != nil {
+		feedback.Fatal(tr("Error during build: %v", err), feedback.ErrGeneric)
+	}
+	if compileCommandsOut != "" {
+		ctx.CompilationDatabase = builder.NewCompilationDatabase()
+	}
+
+	if err := compile.RunBuild(ctx); err != nil {
+		feedback.Fatal(tr("Error during build: %v", err), feedback.ErrGeneric)
+	}
+	saveCompileCommandsOut(ctx.CompilationDatabase)
+
+	if upload {
+		if err := compile.RunUpload(ctx); err != nil {
+			feedback.Fatal(tr("Error during upload: %v", err), feedback.ErrGeneric)
+		}
+	}
+}
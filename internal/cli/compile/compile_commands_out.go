@@ -0,0 +1,45 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2020 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package compile
+
+import (
+	"github.com/arduino/arduino-cli/arduino/builder"
+	"github.com/arduino/arduino-cli/internal/cli/feedback"
+)
+
+// compileCommandsOut is bound to `compile --compile-commands-out <path>`: when
+// set, the compilation database produced for this one sketch is additionally
+// merged into (and atomically saved at) the given path, so a user indexing a
+// library's examples/ one sketch at a time can still end up with a single
+// compile_commands.json covering all of them.
+var compileCommandsOut string
+
+// saveCompileCommandsOut merges db into whatever already exists at
+// compileCommandsOut (if anything) and atomically rewrites it. It is a no-op
+// when the flag wasn't set.
+func saveCompileCommandsOut(db *builder.CompilationDatabase) {
+	if compileCommandsOut == "" || db == nil {
+		return
+	}
+	merged, err := builder.LoadCompilationDatabase(compileCommandsOut)
+	if err != nil {
+		feedback.Fatal(tr("Error reading %s: %v", compileCommandsOut, err), feedback.ErrGeneric)
+	}
+	merged.Merge(db)
+	if err := merged.SaveAtomic(compileCommandsOut); err != nil {
+		feedback.Fatal(tr("Error writing %s: %v", compileCommandsOut, err), feedback.ErrGeneric)
+	}
+}
@@ -0,0 +1,75 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2020 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package compile
+
+import (
+	"context"
+	"os"
+	"os/signal"
+
+	arduinobuilder "github.com/arduino/arduino-cli/arduino/builder"
+	"github.com/arduino/arduino-cli/commands/compile"
+	"github.com/arduino/arduino-cli/i18n"
+	"github.com/arduino/arduino-cli/internal/cli/feedback"
+	"github.com/arduino/arduino-cli/legacy/builder"
+	"github.com/arduino/arduino-cli/legacy/builder/types"
+	rpc "github.com/arduino/arduino-cli/rpc/cc/arduino/cli/commands/v1"
+	"github.com/sirupsen/logrus"
+)
+
+var tr = i18n.Tr
+
+// runCompileWatchCommand keeps a build Context alive and recompiles only the
+// affected translation units every time the sketch directory, libraries/ or
+// an already-discovered #include'd file changes, instead of re-invoking the
+// whole compile pipeline (platform.txt parsing, toolchain resolution,
+// library rescanning) on every edit like a cold `arduino-cli compile` would.
+func runCompileWatchCommand(req *rpc.CompileRequest, shouldUpload bool) {
+	logrus.Info("Executing `arduino-cli compile --watch`")
+
+	ctx, err := compile.NewContext(req)
+	if err != nil {
+		feedback.Fatal(tr("Error during build: %v", err), feedback.ErrGeneric)
+	}
+	if compileCommandsOut != "" {
+		ctx.CompilationDatabase = arduinobuilder.NewCompilationDatabase()
+	}
+
+	rebuild := func(ctx *types.Context) error {
+		if err := compile.RunBuild(ctx); err != nil {
+			return err
+		}
+		saveCompileCommandsOut(ctx.CompilationDatabase)
+		if shouldUpload {
+			return compile.RunUpload(ctx)
+		}
+		return nil
+	}
+
+	watcher := builder.NewWatcher(ctx, rebuild)
+
+	stop := make(chan os.Signal, 1)
+	signal.Notify(stop, os.Interrupt)
+	runCtx, cancel := context.WithCancel(context.Background())
+	go func() {
+		<-stop
+		cancel()
+	}()
+
+	if err := watcher.Run(runCtx); err != nil {
+		feedback.Fatal(tr("Error during watch: %v", err), feedback.ErrGeneric)
+	}
+}
@@ -35,13 +35,14 @@ import (
 )
 
 var (
-	fqbnArg     arguments.Fqbn
-	portArgs    arguments.Port
-	interpreter string
-	importDir   string
-	printInfo   bool
-	programmer  arguments.Programmer
-	tr          = i18n.Tr
+	fqbnArg       arguments.Fqbn
+	portArgs      arguments.Port
+	interpreter   string
+	importDir     string
+	printInfo     bool
+	programmer    arguments.Programmer
+	listenAddress string
+	tr            = i18n.Tr
 )
 
 // NewCommand created a new `upload` command
@@ -58,9 +59,10 @@ func NewCommand() *cobra.Command {
 	fqbnArg.AddToCommand(debugCommand)
 	portArgs.AddToCommand(debugCommand)
 	programmer.AddToCommand(debugCommand)
-	debugCommand.Flags().StringVar(&interpreter, "interpreter", "console", tr("Debug interpreter e.g.: %s", "console, mi, mi1, mi2, mi3"))
+	debugCommand.Flags().StringVar(&interpreter, "interpreter", "console", tr("Debug interpreter e.g.: %s", "console, mi, mi1, mi2, mi3, dap"))
 	debugCommand.Flags().StringVarP(&importDir, "input-dir", "", "", tr("Directory containing binaries for debug."))
 	debugCommand.Flags().BoolVarP(&printInfo, "info", "I", false, tr("Show metadata about the debug session instead of starting the debugger."))
+	debugCommand.Flags().StringVar(&listenAddress, "listen", "", tr("Start only the debug server (e.g. OpenOCD) and expose its GDB remote-serial-protocol port on the given address, e.g. %s, instead of launching an interactive gdb.", ":3333"))
 
 	return debugCommand
 }
@@ -81,16 +83,38 @@ func runDebugCommand(command *cobra.Command, args []string) {
 	}
 	fqbn, port := arguments.CalculateFQBNAndPort(&portArgs, &fqbnArg, instance, sk.GetDefaultFqbn(), sk.GetDefaultPort(), sk.GetDefaultProtocol())
 	debugConfigRequested := &rpc.GetDebugConfigRequest{
-		Instance:    instance,
-		Fqbn:        fqbn,
-		SketchPath:  sketchPath.String(),
-		Port:        port,
-		Interpreter: interpreter,
-		ImportDir:   importDir,
-		Programmer:  programmer.String(),
+		Instance:      instance,
+		Fqbn:          fqbn,
+		SketchPath:    sketchPath.String(),
+		Port:          port,
+		Interpreter:   interpreter,
+		ImportDir:     importDir,
+		Programmer:    programmer.String(),
+		ServerOnly:    listenAddress != "",
+		ListenAddress: listenAddress,
 	}
 
-	if printInfo {
+	if listenAddress != "" {
+
+		// Intercept SIGINT and forward it so the underlying debug server
+		// subprocess (OpenOCD / Black Magic / J-Link GDB server) is shut
+		// down cleanly instead of left running detached.
+		ctrlc := make(chan os.Signal, 1)
+		signal.Notify(ctrlc, os.Interrupt)
+
+		res, session, err := debug.DebugServerOnly(context.Background(), debugConfigRequested)
+		if err != nil {
+			feedback.Fatal(tr("Error during Debug: %v", err), feedback.ErrGeneric)
+		}
+		info := newDebugInfoResult(res)
+		info.RemoteEndpoint = res.GetRemoteEndpoint()
+		feedback.PrintResult(info)
+
+		// Keep the server (and its listener) alive until the user hits
+		// Ctrl-C or the server process exits on its own.
+		session.Wait(ctrlc)
+
+	} else if printInfo {
 
 		if res, err := debug.GetDebugConfig(context.Background(), debugConfigRequested); err != nil {
 			feedback.Fatal(tr("Error getting Debug info: %v", err), feedback.ErrBadArgument)
@@ -104,11 +128,20 @@ func runDebugCommand(command *cobra.Command, args []string) {
 		ctrlc := make(chan os.Signal, 1)
 		signal.Notify(ctrlc, os.Interrupt)
 
+		// in/out are the raw stdio streams: RunDAP writes its
+		// Content-Length-framed JSON directly to out, bypassing feedback's
+		// color/table rendering entirely, since an editor, not a human, is
+		// on the other end in "dap" mode.
 		in, out, err := feedback.InteractiveStreams()
 		if err != nil {
 			feedback.FatalError(err, feedback.ErrBadArgument)
 		}
-		if _, err := debug.Debug(context.Background(), debugConfigRequested, in, out, ctrlc); err != nil {
+
+		if interpreter == "dap" {
+			if err := debug.RunDAP(context.Background(), debugConfigRequested, in, out, ctrlc); err != nil {
+				feedback.Fatal(tr("Error during Debug: %v", err), feedback.ErrGeneric)
+			}
+		} else if _, err := debug.Debug(context.Background(), debugConfigRequested, in, out, ctrlc); err != nil {
 			feedback.Fatal(tr("Error during Debug: %v", err), feedback.ErrGeneric)
 		}
 
@@ -127,6 +160,7 @@ type debugInfoResult struct {
 	SvdFile                 string `json:"svd_file,omitempty"`
 	CortexDebugCustomConfig any    `json:"cortex-debug_custom_configuration,omitempty"`
 	Programmer              string `json:"programmer"`
+	RemoteEndpoint          string `json:"remote_endpoint,omitempty"`
 }
 
 type openOcdServerConfigResult struct {
@@ -135,6 +169,13 @@ type openOcdServerConfigResult struct {
 	Scripts    []string `json:"scripts,omitempty"`
 }
 
+type blackMagicServerConfigResult struct {
+	GDBPort        string   `json:"gdb_port,omitempty"`
+	ScanCommand    string   `json:"scan_command,omitempty"`
+	AttachTarget   string   `json:"attach_target,omitempty"`
+	MonitorPreInit []string `json:"monitor_pre_init,omitempty"`
+}
+
 func newDebugInfoResult(info *rpc.GetDebugConfigResponse) *debugInfoResult {
 	var toolchainConfig interface{}
 	var serverConfig interface{}
@@ -149,6 +190,17 @@ func newDebugInfoResult(info *rpc.GetDebugConfigResponse) *debugInfoResult {
 			ScriptsDir: openocdConf.ScriptsDir,
 			Scripts:    openocdConf.Scripts,
 		}
+	case "blackmagic":
+		var bmpConf rpc.DebugBlackMagicProbeServerConfiguration
+		if err := info.GetServerConfiguration().UnmarshalTo(&bmpConf); err != nil {
+			feedback.Fatal(tr("Error during Debug: %v", err), feedback.ErrGeneric)
+		}
+		serverConfig = &blackMagicServerConfigResult{
+			GDBPort:        bmpConf.GdbPort,
+			ScanCommand:    bmpConf.ScanCmd,
+			AttachTarget:   bmpConf.AttachTarget,
+			MonitorPreInit: bmpConf.MonitorPreInit,
+		}
 	}
 	var cortexDebugCustomConfig any
 	if info.CortexDebugCustomJson != "" {
@@ -193,6 +245,9 @@ func (r *debugInfoResult) String() string {
 	}
 	t.AddRow(tr("Server type"), table.NewCell(r.Server, green))
 	t.AddRow(tr("Server path"), table.NewCell(r.ServerPath, dimGreen))
+	if r.RemoteEndpoint != "" {
+		t.AddRow(tr("Remote GDB endpoint"), table.NewCell(r.RemoteEndpoint, green))
+	}
 
 	switch r.Server {
 	case "openocd":
@@ -207,6 +262,15 @@ func (r *debugInfoResult) String() string {
 		for _, script := range openocdConf.Scripts {
 			t.AddRow(" - Script", table.NewCell(script, dimGreen))
 		}
+	case "blackmagic":
+		t.AddRow(tr("Configuration options for %s", r.Server))
+		bmpConf := r.ServerConfig.(*blackMagicServerConfigResult)
+		t.AddRow(" - GDB port", table.NewCell(bmpConf.GDBPort, dimGreen))
+		t.AddRow(" - Scan command", table.NewCell(bmpConf.ScanCommand, dimGreen))
+		t.AddRow(" - Attach target", table.NewCell(bmpConf.AttachTarget, dimGreen))
+		for _, monitor := range bmpConf.MonitorPreInit {
+			t.AddRow(" - Monitor pre-init", table.NewCell(monitor, dimGreen))
+		}
 	default:
 	}
 	if r.CortexDebugCustomConfig != nil {
@@ -0,0 +1,103 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2020 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package upload
+
+import (
+	"github.com/arduino/arduino-cli/commands/upload"
+	"github.com/arduino/arduino-cli/i18n"
+	"github.com/arduino/arduino-cli/internal/cli/feedback"
+	"github.com/arduino/arduino-cli/internal/cli/instance"
+	"github.com/arduino/arduino-cli/table"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+var tr = i18n.Tr
+
+// newPluginsCommand is added as a subcommand by the upload command's
+// NewCommand() via uploadCommand.AddCommand(newPluginsCommand()).
+func newPluginsCommand() *cobra.Command {
+	pluginsCommand := &cobra.Command{
+		Use:   "plugins",
+		Short: tr("Manage external uploader plugins."),
+	}
+	pluginsCommand.AddCommand(newPluginsListCommand())
+	return pluginsCommand
+}
+
+func newPluginsListCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "list",
+		Short: tr("List the external-uploader plugins registered by installed platforms."),
+		Args:  cobra.NoArgs,
+		Run:   runPluginsListCommand,
+	}
+}
+
+func runPluginsListCommand(cmd *cobra.Command, args []string) {
+	inst := instance.CreateAndInit()
+	logrus.Info("Executing `arduino-cli upload plugins list`")
+
+	plugins, err := upload.ListUploaderPlugins(instance.GetPackageManagerExplorer(inst))
+	if err != nil {
+		feedback.Fatal(tr("Error listing uploader plugins: %v", err), feedback.ErrGeneric)
+	}
+
+	result := make([]*uploaderPluginResult, len(plugins))
+	for i, p := range plugins {
+		result[i] = &uploaderPluginResult{
+			Name:        p.Name,
+			ToolPath:    p.ToolPath.String(),
+			ProducesUF2: p.ProducesUF2,
+			Args:        p.ArgsTemplate,
+		}
+	}
+	feedback.PrintResult(pluginsListResult{plugins: result})
+}
+
+type pluginsListResult struct {
+	plugins []*uploaderPluginResult
+}
+
+type uploaderPluginResult struct {
+	Name        string   `json:"name"`
+	ToolPath    string   `json:"tool_path"`
+	ProducesUF2 bool     `json:"produces_uf2"`
+	Args        []string `json:"args"`
+}
+
+func (r pluginsListResult) Data() interface{} {
+	return r.plugins
+}
+
+func (r pluginsListResult) String() string {
+	if len(r.plugins) == 0 {
+		return tr("No uploader plugins registered.")
+	}
+	t := table.New()
+	t.SetHeader(tr("Name"), tr("Tool"), tr("UF2"))
+	for _, p := range r.plugins {
+		t.AddRow(p.Name, p.ToolPath, boolYesNo(p.ProducesUF2))
+	}
+	return t.Render()
+}
+
+func boolYesNo(b bool) string {
+	if b {
+		return tr("yes")
+	}
+	return tr("no")
+}
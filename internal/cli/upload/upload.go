@@ -0,0 +1,150 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2020 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+// Package upload implements `arduino-cli upload`.
+package upload
+
+import (
+	"context"
+	"os"
+	"strings"
+
+	"github.com/arduino/arduino-cli/commands/sketch"
+	"github.com/arduino/arduino-cli/commands/upload"
+	"github.com/arduino/arduino-cli/internal/cli/arguments"
+	"github.com/arduino/arduino-cli/internal/cli/feedback"
+	"github.com/arduino/arduino-cli/internal/cli/instance"
+	"github.com/arduino/arduino-cli/legacy/builder"
+	"github.com/arduino/arduino-cli/legacy/builder/types"
+	rpc "github.com/arduino/arduino-cli/rpc/cc/arduino/cli/commands/v1"
+	paths "github.com/arduino/go-paths-helper"
+	properties "github.com/arduino/go-properties-orderedmap"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+var (
+	fqbnArg    arguments.Fqbn
+	portArgs   arguments.Port
+	programmer arguments.Programmer
+	verify     bool
+	verbose    bool
+	serialPort string
+	buildDir   string
+	pluginTool string
+)
+
+// pluginToolPrefix is the --tool value prefix that routes an upload through
+// the registered external-uploader plugins (see plugins.go) instead of the
+// board's own avrdude/bossac/openocd recipe.
+const pluginToolPrefix = "plugin:"
+
+// NewCommand created a new `upload` command.
+func NewCommand() *cobra.Command {
+	uploadCommand := &cobra.Command{
+		Use:   "upload",
+		Short: tr("Upload Arduino sketches."),
+		Example: "  " + os.Args[0] + " upload -b arduino:avr:uno -p /dev/ttyACM0 /home/user/Arduino/MySketch" +
+			"\n  " + os.Args[0] + " upload --tool plugin:blisp --port /dev/ttyACM0 /home/user/Arduino/MySketch/build",
+		Args: cobra.MaximumNArgs(1),
+		Run:  runUploadCommand,
+	}
+
+	fqbnArg.AddToCommand(uploadCommand)
+	portArgs.AddToCommand(uploadCommand)
+	programmer.AddToCommand(uploadCommand)
+	uploadCommand.Flags().BoolVarP(&verify, "verify", "t", false, tr("Verify uploaded binary after the upload."))
+	uploadCommand.Flags().BoolVarP(&verbose, "verbose", "v", false, tr("Optional, turns on verbose mode."))
+	uploadCommand.Flags().StringVar(&serialPort, "serial-port", "", tr("Serial port to hand to the uploader plugin, if different from --port."))
+	uploadCommand.Flags().StringVarP(&buildDir, "input-dir", "", "", tr("Directory containing binaries to upload."))
+	uploadCommand.Flags().StringVar(&pluginTool, "tool", "", tr("Upload tool to use, e.g. %s to upload through a registered external-uploader plugin instead of the board's own recipe.", "plugin:<name>"))
+
+	uploadCommand.AddCommand(newPluginsCommand())
+	return uploadCommand
+}
+
+func runUploadCommand(cmd *cobra.Command, args []string) {
+	logrus.Info("Executing `arduino-cli upload`")
+
+	if pluginTool != "" && !strings.HasPrefix(pluginTool, pluginToolPrefix) {
+		feedback.Fatal(tr("Invalid upload tool %s: expected %s", pluginTool, "plugin:<name>"), feedback.ErrBadArgument)
+	}
+	if strings.HasPrefix(pluginTool, pluginToolPrefix) {
+		// The plugin path uploads a directory of already-built binaries, not
+		// a sketch, so it skips sketch/FQBN resolution entirely.
+		runPluginUploadCommand(portArgs.String())
+		return
+	}
+
+	path := ""
+	if len(args) > 0 {
+		path = args[0]
+	}
+
+	inst := instance.CreateAndInit()
+	sketchPath := arguments.InitSketchPath(path, true)
+	sk, err := sketch.LoadSketch(context.Background(), &rpc.LoadSketchRequest{SketchPath: sketchPath.String()})
+	if err != nil {
+		feedback.FatalError(err, feedback.ErrGeneric)
+	}
+	fqbn, port := arguments.CalculateFQBNAndPort(&portArgs, &fqbnArg, inst, sk.GetDefaultFqbn(), sk.GetDefaultPort(), sk.GetDefaultProtocol())
+
+	req := &rpc.UploadRequest{
+		Instance:   inst,
+		Fqbn:       fqbn,
+		SketchPath: sketchPath.String(),
+		Port:       port,
+		Verbose:    verbose,
+		Verify:     verify,
+		ImportDir:  buildDir,
+		Programmer: programmer.String(),
+	}
+	if _, err := upload.Upload(context.Background(), req, os.Stdout, os.Stderr); err != nil {
+		feedback.Fatal(tr("Error during upload: %v", err), feedback.ErrGeneric)
+	}
+}
+
+// runPluginUploadCommand handles `upload --tool plugin:<name>`, dispatching
+// through the registered external-uploader plugins (see plugins.go) instead
+// of the board's FQBN-resolved recipe.
+func runPluginUploadCommand(uploadPort string) {
+	if serialPort == "" {
+		serialPort = uploadPort
+	}
+
+	inst := instance.CreateAndInit()
+	plugins, err := upload.LoadUploaderPlugins(instance.GetPackageManagerExplorer(inst))
+	if err != nil {
+		feedback.Fatal(tr("Error loading uploader plugins: %v", err), feedback.ErrGeneric)
+	}
+
+	props := properties.NewMap()
+	props.Set("upload.tool", pluginTool)
+	ctx := &types.Context{
+		BuildProperties: props,
+		BuildPath:       paths.New(buildDir),
+		Uploaders:       plugins,
+		Stdout:          os.Stdout,
+		Stderr:          os.Stderr,
+	}
+
+	handled, err := builder.RunPluginUpload(ctx, uploadPort, serialPort)
+	if !handled {
+		feedback.Fatal(tr("upload.tool=%s does not name a registered uploader plugin", pluginTool), feedback.ErrBadArgument)
+	}
+	if err != nil {
+		feedback.Fatal(tr("Error during upload: %v", err), feedback.ErrGeneric)
+	}
+}
@@ -0,0 +1,153 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2020 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+// Package workspace implements `arduino-cli workspace`, commands that operate
+// on a folder of sketches as a whole rather than on a single one.
+package workspace
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sync"
+
+	"github.com/arduino/arduino-cli/arduino/builder"
+	"github.com/arduino/arduino-cli/commands/compile"
+	"github.com/arduino/arduino-cli/i18n"
+	"github.com/arduino/arduino-cli/internal/cli/feedback"
+	"github.com/arduino/arduino-cli/internal/cli/instance"
+	rpc "github.com/arduino/arduino-cli/rpc/cc/arduino/cli/commands/v1"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+var (
+	tr         = i18n.Tr
+	jobs       int
+	outputPath string
+)
+
+// NewCommand created a new `workspace` command.
+func NewCommand() *cobra.Command {
+	workspaceCommand := &cobra.Command{
+		Use:   "workspace",
+		Short: tr("Commands that operate on a folder of sketches as a whole."),
+	}
+	workspaceCommand.AddCommand(newIndexCommand())
+	return workspaceCommand
+}
+
+func newIndexCommand() *cobra.Command {
+	indexCommand := &cobra.Command{
+		Use:   "index <dir>",
+		Short: tr("Build a merged compile_commands.json covering every sketch under <dir>."),
+		Long:  tr("Walks <dir> for sketches, runs each one through the compile pipeline in compilation-database-only mode, and merges the results into a single compile_commands.json so clangd/ccls can index the whole workspace instead of just one sketch at a time."),
+		Args:  cobra.ExactArgs(1),
+		Run:   runIndexCommand,
+	}
+	indexCommand.Flags().IntVar(&jobs, "jobs", 0, tr("Number of sketches to index in parallel (defaults to the number of CPUs)."))
+	indexCommand.Flags().StringVar(&outputPath, "output", "compile_commands.json", tr("Path to write the merged compilation database to."))
+	return indexCommand
+}
+
+func runIndexCommand(cmd *cobra.Command, args []string) {
+	inst := instance.CreateAndInit()
+	logrus.Info("Executing `arduino-cli workspace index`")
+
+	sketchPaths, err := findSketches(args[0])
+	if err != nil {
+		feedback.Fatal(tr("Error scanning %s for sketches: %v", args[0], err), feedback.ErrGeneric)
+	}
+	if len(sketchPaths) == 0 {
+		feedback.Fatal(tr("No sketches found under %s", args[0]), feedback.ErrGeneric)
+	}
+
+	merged := builder.NewCompilationDatabase()
+	var mergedLock sync.Mutex
+
+	parallelism := jobs
+	if parallelism <= 0 {
+		parallelism = runtime.NumCPU()
+	}
+	sem := make(chan struct{}, parallelism)
+	var wg sync.WaitGroup
+	var firstErr error
+	var errOnce sync.Once
+
+	for _, sketchPath := range sketchPaths {
+		sketchPath := sketchPath
+		wg.Add(1)
+		sem <- struct{}{}
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			db, err := compile.OnlyUpdateCompilationDatabase(context.Background(), &rpc.CompileRequest{
+				Instance:   inst,
+				SketchPath: sketchPath,
+			})
+			if err != nil {
+				errOnce.Do(func() { firstErr = err })
+				return
+			}
+			mergedLock.Lock()
+			merged.Merge(db)
+			mergedLock.Unlock()
+		}()
+	}
+	wg.Wait()
+
+	if firstErr != nil {
+		feedback.Fatal(tr("Error indexing workspace: %v", firstErr), feedback.ErrGeneric)
+	}
+	if err := merged.SaveAtomic(outputPath); err != nil {
+		feedback.Fatal(tr("Error writing %s: %v", outputPath, err), feedback.ErrGeneric)
+	}
+
+	feedback.PrintResult(indexResult{SketchCount: len(sketchPaths), OutputPath: outputPath})
+}
+
+type indexResult struct {
+	SketchCount int    `json:"sketch_count"`
+	OutputPath  string `json:"output_path"`
+}
+
+func (r indexResult) Data() interface{} {
+	return r
+}
+
+func (r indexResult) String() string {
+	return tr("Indexed %d sketches into %s", r.SketchCount, r.OutputPath)
+}
+
+// findSketches walks dir looking for directories containing a .ino file of
+// the same name, the same layout `compile` itself accepts as a sketch.
+func findSketches(dir string) ([]string, error) {
+	var sketches []string
+	err := filepath.WalkDir(dir, func(path string, d os.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		if filepath.Ext(path) != ".ino" {
+			return nil
+		}
+		if filepath.Base(path) == filepath.Base(filepath.Dir(path))+".ino" {
+			sketches = append(sketches, filepath.Dir(path))
+		}
+		return nil
+	})
+	return sketches, err
+}
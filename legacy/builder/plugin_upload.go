@@ -0,0 +1,85 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2020 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package builder
+
+import (
+	"fmt"
+	"os/exec"
+	"strings"
+
+	"github.com/arduino/arduino-cli/legacy/builder/types"
+)
+
+// pluginToolPrefix is the upload.tool value prefix (upload.tool=plugin:<name>)
+// that routes an upload through an external-uploader plugin instead of the
+// usual avrdude/bossac/openocd recipe.
+const pluginToolPrefix = "plugin:"
+
+// RunPluginUpload runs the external-uploader plugin named by
+// ctx.BuildProperties's upload.tool, if it is set to "plugin:<name>".
+// handled is false (with a nil error) when upload.tool doesn't name a
+// plugin, so the caller falls back to the normal recipe-based upload.
+func RunPluginUpload(ctx *types.Context, uploadPort, serialPort string) (handled bool, err error) {
+	tool := ctx.BuildProperties.Get("upload.tool")
+	if !strings.HasPrefix(tool, pluginToolPrefix) {
+		return false, nil
+	}
+
+	name := strings.TrimPrefix(tool, pluginToolPrefix)
+	plugin, ok := ctx.Uploaders[name]
+	if !ok {
+		return true, fmt.Errorf("upload.tool=%s: no such uploader plugin registered", tool)
+	}
+
+	if plugin.ProducesUF2 {
+		if err := convertBuildOutputToUF2(ctx.BuildPath, plugin.FlashAddress); err != nil {
+			return true, fmt.Errorf("converting build output to UF2 for uploader plugin %s: %w", name, err)
+		}
+	}
+
+	args := plugin.PlaceholderArgs(ctx.BuildPath.String(), uploadPort, serialPort)
+	cmd := exec.Command(plugin.ToolPath.String(), args...)
+	cmd.Stdout = ctx.Stdout
+	cmd.Stderr = ctx.Stderr
+
+	runErr := cmd.Run()
+	if pluginExitCodeExpected(plugin, cmd) {
+		return true, nil
+	}
+	if runErr != nil {
+		return true, fmt.Errorf("running uploader plugin %s: %w", name, runErr)
+	}
+	return true, fmt.Errorf("uploader plugin %s exited with unexpected code %d", name, cmd.ProcessState.ExitCode())
+}
+
+// pluginExitCodeExpected reports whether cmd's exit code is one of the
+// plugin's ExpectedExitCodes (defaulting to just 0 when unset).
+func pluginExitCodeExpected(plugin *types.UploaderPlugin, cmd *exec.Cmd) bool {
+	if cmd.ProcessState == nil {
+		return false
+	}
+	code := cmd.ProcessState.ExitCode()
+	expected := plugin.ExpectedExitCodes
+	if len(expected) == 0 {
+		expected = []int{0}
+	}
+	for _, e := range expected {
+		if e == code {
+			return true
+		}
+	}
+	return false
+}
@@ -104,6 +104,13 @@ type Context struct {
 	// The provided source data is used instead of reading it from disk.
 	// The keys of the map are paths relative to sketch folder.
 	SourceOverride map[string]string
+
+	// Uploaders holds the external-uploader plugins (e.g. blisp, elf2uf2-rs,
+	// openFPGAloader) registered by installed platforms, keyed by name so
+	// platform.txt can reference one via upload.tool=plugin:<name>. Checked by
+	// builder.RunPluginUpload before falling back to the normal recipe-based
+	// upload.
+	Uploaders map[string]*UploaderPlugin
 }
 
 func (ctx *Context) ExtractBuildOptions() *properties.Map {
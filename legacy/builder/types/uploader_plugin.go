@@ -0,0 +1,63 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2020 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package types
+
+import (
+	"strings"
+
+	paths "github.com/arduino/go-paths-helper"
+)
+
+// UploaderPlugin describes a third-party uploader tool (e.g. blisp,
+// elf2uf2-rs, openFPGAloader) registered by a platform's tools/ directory,
+// so platform.txt can use it via `upload.tool=plugin:<name>` instead of a
+// hand-written avrdude/bossac/openocd recipe.
+type UploaderPlugin struct {
+	// Name is how platform.txt refers to this plugin: upload.tool=plugin:<Name>.
+	Name string
+	// ToolPath is the resolved path to the plugin executable.
+	ToolPath *paths.Path
+	// ArgsTemplate is the argv template, e.g.
+	// ["--port", "{serial.port}", "--flash", "{build.path}/sketch.bin"].
+	ArgsTemplate []string
+	// ExpectedExitCodes lists the process exit codes considered success.
+	// Defaults to []int{0} when left empty.
+	ExpectedExitCodes []int
+	// ProducesUF2 is true when the plugin's input is a .uf2 intermediate
+	// (converted from the build's .bin) rather than the raw binary. When
+	// set, RunPluginUpload converts every .bin file under the build
+	// directory to a sibling .uf2 file, embedding FlashAddress, before
+	// invoking the plugin.
+	ProducesUF2 bool
+	// FlashAddress is the target flash offset embedded in each UF2 block
+	// written when ProducesUF2 is set. Meaningless otherwise.
+	FlashAddress uint32
+}
+
+// PlaceholderArgs expands {build.path}, {upload.port} and {serial.port}
+// placeholders in ArgsTemplate against the given values.
+func (p *UploaderPlugin) PlaceholderArgs(buildPath, uploadPort, serialPort string) []string {
+	replacer := strings.NewReplacer(
+		"{build.path}", buildPath,
+		"{upload.port}", uploadPort,
+		"{serial.port}", serialPort,
+	)
+	args := make([]string, len(p.ArgsTemplate))
+	for i, a := range p.ArgsTemplate {
+		args[i] = replacer.Replace(a)
+	}
+	return args
+}
@@ -0,0 +1,49 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2020 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package types
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestUploaderPluginPlaceholderArgs(t *testing.T) {
+	plugin := &UploaderPlugin{
+		Name: "blisp",
+		ArgsTemplate: []string{
+			"--port", "{upload.port}",
+			"--serial", "{serial.port}",
+			"--flash", "{build.path}/sketch.bin",
+		},
+	}
+
+	args := plugin.PlaceholderArgs("/tmp/build", "/dev/ttyACM0", "/dev/ttyUSB0")
+
+	require.Equal(t, []string{
+		"--port", "/dev/ttyACM0",
+		"--serial", "/dev/ttyUSB0",
+		"--flash", "/tmp/build/sketch.bin",
+	}, args)
+}
+
+func TestUploaderPluginPlaceholderArgsNoPlaceholders(t *testing.T) {
+	plugin := &UploaderPlugin{ArgsTemplate: []string{"--verbose"}}
+
+	args := plugin.PlaceholderArgs("/tmp/build", "/dev/ttyACM0", "/dev/ttyUSB0")
+
+	require.Equal(t, []string{"--verbose"}, args)
+}
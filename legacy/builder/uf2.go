@@ -0,0 +1,103 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2020 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+package builder
+
+import (
+	"encoding/binary"
+	"fmt"
+
+	paths "github.com/arduino/go-paths-helper"
+)
+
+// uf2MagicStart0, uf2MagicStart1 and uf2MagicEnd are the fixed values every
+// UF2 block header/footer must carry, per the format's spec
+// (https://github.com/microsoft/uf2).
+const (
+	uf2MagicStart0 uint32 = 0x0A324655
+	uf2MagicStart1 uint32 = 0x9E5D5157
+	uf2MagicEnd    uint32 = 0x0AB16F30
+
+	uf2BlockSize   = 512
+	uf2DataPerBlk  = 476
+	uf2FlagNone    = 0
+	uf2FamilyIDAbs = 0
+)
+
+// convertToUF2 reads the raw firmware image at binPath (the build's .bin/.elf
+// converted to a flat binary by the usual recipe) and writes it out as a UF2
+// file alongside it, chunked into uf2DataPerBlk-byte blocks starting at
+// flashAddr. It returns the path to the new .uf2 file.
+func convertToUF2(binPath *paths.Path, flashAddr uint32) (*paths.Path, error) {
+	data, err := binPath.ReadFile()
+	if err != nil {
+		return nil, fmt.Errorf("reading %s: %w", binPath, err)
+	}
+
+	numBlocks := (len(data) + uf2DataPerBlk - 1) / uf2DataPerBlk
+	if numBlocks == 0 {
+		numBlocks = 1
+	}
+
+	out := make([]byte, 0, numBlocks*uf2BlockSize)
+	for i := 0; i < numBlocks; i++ {
+		start := i * uf2DataPerBlk
+		end := start + uf2DataPerBlk
+		if end > len(data) {
+			end = len(data)
+		}
+		chunk := data[start:end]
+
+		block := make([]byte, uf2BlockSize)
+		binary.LittleEndian.PutUint32(block[0:], uf2MagicStart0)
+		binary.LittleEndian.PutUint32(block[4:], uf2MagicStart1)
+		binary.LittleEndian.PutUint32(block[8:], uf2FlagNone)
+		binary.LittleEndian.PutUint32(block[12:], flashAddr+uint32(start))
+		binary.LittleEndian.PutUint32(block[16:], uint32(len(chunk)))
+		binary.LittleEndian.PutUint32(block[20:], uint32(i))
+		binary.LittleEndian.PutUint32(block[24:], uint32(numBlocks))
+		binary.LittleEndian.PutUint32(block[28:], uf2FamilyIDAbs)
+		copy(block[32:], chunk)
+		binary.LittleEndian.PutUint32(block[508:], uf2MagicEnd)
+
+		out = append(out, block...)
+	}
+
+	uf2Path := binPath.Parent().Join(binPath.Base() + ".uf2")
+	if err := uf2Path.WriteFile(out); err != nil {
+		return nil, fmt.Errorf("writing %s: %w", uf2Path, err)
+	}
+	return uf2Path, nil
+}
+
+// convertBuildOutputToUF2 converts every .bin file directly under buildPath
+// to a sibling .uf2 file (e.g. sketch.ino.bin -> sketch.ino.bin.uf2), for
+// uploader plugins that take a UF2 image rather than the raw binary.
+func convertBuildOutputToUF2(buildPath *paths.Path, flashAddr uint32) error {
+	entries, err := buildPath.ReadDir()
+	if err != nil {
+		return fmt.Errorf("reading %s: %w", buildPath, err)
+	}
+
+	for _, entry := range entries {
+		if entry.Ext() != ".bin" {
+			continue
+		}
+		if _, err := convertToUF2(entry, flashAddr); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,274 @@
+// This file is part of arduino-cli.
+//
+// Copyright 2020 ARDUINO SA (http://www.arduino.cc/)
+//
+// This software is released under the GNU General Public License version 3,
+// which covers the main part of arduino-cli.
+// The terms of this license can be found at:
+// https://www.gnu.org/licenses/gpl-3.0.en.html
+//
+// You can be released from the requirements of the above licenses by purchasing
+// a commercial license. Buying such a license is mandatory if you want to
+// modify or otherwise use the software for commercial activities involving the
+// Arduino software without disclosing the source code of your own applications.
+// To purchase a commercial license, send an email to license@arduino.cc.
+
+// DRAFT: debug.proto has no generated Go bindings in this tree. protoc and
+// protoc-gen-go aren't available to produce them, and the common.proto types
+// debug.proto imports (Instance, Port) aren't checked in either, so `task
+// protoc:compile` cannot be run here. This file is a hand-written stand-in,
+// not the real generated output — do not extend it as though it were; run
+// protoc:compile and replace it wholesale once the generator toolchain and
+// common.proto are available.
+//
+// ProtoReflect() on each type is backed by protoadapt.MessageV2Of, which
+// derives a protoreflect.Message from the `protobuf:"..."` struct tags at
+// runtime instead of a generated file descriptor. That happens to be enough
+// to make proto.Marshal/proto.Unmarshal (and anypb.New/UnmarshalTo, which
+// both marshal through it) work correctly, but it is a workaround for the
+// missing generator, not a design choice.
+package commands
+
+import (
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/protoadapt"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/runtime/protoimpl"
+	"google.golang.org/protobuf/types/known/anypb"
+)
+
+type GetDebugConfigRequest struct {
+	Instance      *Instance `protobuf:"bytes,1,opt,name=instance,proto3" json:"instance,omitempty"`
+	Fqbn          string    `protobuf:"bytes,2,opt,name=fqbn,proto3" json:"fqbn,omitempty"`
+	SketchPath    string    `protobuf:"bytes,3,opt,name=sketch_path,json=sketchPath,proto3" json:"sketch_path,omitempty"`
+	Port          *Port     `protobuf:"bytes,4,opt,name=port,proto3" json:"port,omitempty"`
+	Interpreter   string    `protobuf:"bytes,5,opt,name=interpreter,proto3" json:"interpreter,omitempty"`
+	ImportDir     string    `protobuf:"bytes,6,opt,name=import_dir,json=importDir,proto3" json:"import_dir,omitempty"`
+	Programmer    string    `protobuf:"bytes,7,opt,name=programmer,proto3" json:"programmer,omitempty"`
+	ServerOnly    bool      `protobuf:"varint,8,opt,name=server_only,json=serverOnly,proto3" json:"server_only,omitempty"`
+	ListenAddress string    `protobuf:"bytes,9,opt,name=listen_address,json=listenAddress,proto3" json:"listen_address,omitempty"`
+}
+
+func (x *GetDebugConfigRequest) Reset()         { *x = GetDebugConfigRequest{} }
+func (x *GetDebugConfigRequest) String() string { return protoimpl.X.MessageStringOf(x) }
+func (*GetDebugConfigRequest) ProtoMessage()    {}
+func (x *GetDebugConfigRequest) ProtoReflect() protoreflect.Message {
+	return protoadapt.MessageV2Of(x).ProtoReflect()
+}
+
+func (x *GetDebugConfigRequest) GetInstance() *Instance {
+	if x != nil {
+		return x.Instance
+	}
+	return nil
+}
+func (x *GetDebugConfigRequest) GetFqbn() string {
+	if x != nil {
+		return x.Fqbn
+	}
+	return ""
+}
+func (x *GetDebugConfigRequest) GetSketchPath() string {
+	if x != nil {
+		return x.SketchPath
+	}
+	return ""
+}
+func (x *GetDebugConfigRequest) GetPort() *Port {
+	if x != nil {
+		return x.Port
+	}
+	return nil
+}
+func (x *GetDebugConfigRequest) GetInterpreter() string {
+	if x != nil {
+		return x.Interpreter
+	}
+	return ""
+}
+func (x *GetDebugConfigRequest) GetImportDir() string {
+	if x != nil {
+		return x.ImportDir
+	}
+	return ""
+}
+func (x *GetDebugConfigRequest) GetProgrammer() string {
+	if x != nil {
+		return x.Programmer
+	}
+	return ""
+}
+func (x *GetDebugConfigRequest) GetServerOnly() bool {
+	if x != nil {
+		return x.ServerOnly
+	}
+	return false
+}
+func (x *GetDebugConfigRequest) GetListenAddress() string {
+	if x != nil {
+		return x.ListenAddress
+	}
+	return ""
+}
+
+type GetDebugConfigResponse struct {
+	Executable             string     `protobuf:"bytes,1,opt,name=executable,proto3" json:"executable,omitempty"`
+	Toolchain              string     `protobuf:"bytes,2,opt,name=toolchain,proto3" json:"toolchain,omitempty"`
+	ToolchainPath          string     `protobuf:"bytes,3,opt,name=toolchain_path,json=toolchainPath,proto3" json:"toolchain_path,omitempty"`
+	ToolchainPrefix        string     `protobuf:"bytes,4,opt,name=toolchain_prefix,json=toolchainPrefix,proto3" json:"toolchain_prefix,omitempty"`
+	ToolchainConfiguration *anypb.Any `protobuf:"bytes,5,opt,name=toolchain_configuration,json=toolchainConfiguration,proto3" json:"toolchain_configuration,omitempty"`
+	Server                 string     `protobuf:"bytes,6,opt,name=server,proto3" json:"server,omitempty"`
+	ServerPath             string     `protobuf:"bytes,7,opt,name=server_path,json=serverPath,proto3" json:"server_path,omitempty"`
+	ServerConfiguration    *anypb.Any `protobuf:"bytes,8,opt,name=server_configuration,json=serverConfiguration,proto3" json:"server_configuration,omitempty"`
+	SvdFile                string     `protobuf:"bytes,9,opt,name=svd_file,json=svdFile,proto3" json:"svd_file,omitempty"`
+	CortexDebugCustomJson  string     `protobuf:"bytes,10,opt,name=cortex_debug_custom_json,json=cortexDebugCustomJson,proto3" json:"cortex_debug_custom_json,omitempty"`
+	Programmer             string     `protobuf:"bytes,11,opt,name=programmer,proto3" json:"programmer,omitempty"`
+	RemoteEndpoint         string     `protobuf:"bytes,12,opt,name=remote_endpoint,json=remoteEndpoint,proto3" json:"remote_endpoint,omitempty"`
+}
+
+func (x *GetDebugConfigResponse) Reset()         { *x = GetDebugConfigResponse{} }
+func (x *GetDebugConfigResponse) String() string { return protoimpl.X.MessageStringOf(x) }
+func (*GetDebugConfigResponse) ProtoMessage()    {}
+func (x *GetDebugConfigResponse) ProtoReflect() protoreflect.Message {
+	return protoadapt.MessageV2Of(x).ProtoReflect()
+}
+
+func (x *GetDebugConfigResponse) GetExecutable() string {
+	if x != nil {
+		return x.Executable
+	}
+	return ""
+}
+func (x *GetDebugConfigResponse) GetToolchain() string {
+	if x != nil {
+		return x.Toolchain
+	}
+	return ""
+}
+func (x *GetDebugConfigResponse) GetToolchainPath() string {
+	if x != nil {
+		return x.ToolchainPath
+	}
+	return ""
+}
+func (x *GetDebugConfigResponse) GetToolchainPrefix() string {
+	if x != nil {
+		return x.ToolchainPrefix
+	}
+	return ""
+}
+func (x *GetDebugConfigResponse) GetToolchainConfiguration() *anypb.Any {
+	if x != nil {
+		return x.ToolchainConfiguration
+	}
+	return nil
+}
+func (x *GetDebugConfigResponse) GetServer() string {
+	if x != nil {
+		return x.Server
+	}
+	return ""
+}
+func (x *GetDebugConfigResponse) GetServerPath() string {
+	if x != nil {
+		return x.ServerPath
+	}
+	return ""
+}
+func (x *GetDebugConfigResponse) GetServerConfiguration() *anypb.Any {
+	if x != nil {
+		return x.ServerConfiguration
+	}
+	return nil
+}
+func (x *GetDebugConfigResponse) GetSvdFile() string {
+	if x != nil {
+		return x.SvdFile
+	}
+	return ""
+}
+func (x *GetDebugConfigResponse) GetCortexDebugCustomJson() string {
+	if x != nil {
+		return x.CortexDebugCustomJson
+	}
+	return ""
+}
+func (x *GetDebugConfigResponse) GetProgrammer() string {
+	if x != nil {
+		return x.Programmer
+	}
+	return ""
+}
+func (x *GetDebugConfigResponse) GetRemoteEndpoint() string {
+	if x != nil {
+		return x.RemoteEndpoint
+	}
+	return ""
+}
+
+// DebugOpenOCDServerConfiguration is the server_configuration payload when
+// Server == "openocd".
+type DebugOpenOCDServerConfiguration struct {
+	Path       string   `protobuf:"bytes,1,opt,name=path,proto3" json:"path,omitempty"`
+	ScriptsDir string   `protobuf:"bytes,2,opt,name=scripts_dir,json=scriptsDir,proto3" json:"scripts_dir,omitempty"`
+	Scripts    []string `protobuf:"bytes,3,rep,name=scripts,proto3" json:"scripts,omitempty"`
+}
+
+func (x *DebugOpenOCDServerConfiguration) Reset()         { *x = DebugOpenOCDServerConfiguration{} }
+func (x *DebugOpenOCDServerConfiguration) String() string { return protoimpl.X.MessageStringOf(x) }
+func (*DebugOpenOCDServerConfiguration) ProtoMessage()    {}
+func (x *DebugOpenOCDServerConfiguration) ProtoReflect() protoreflect.Message {
+	return protoadapt.MessageV2Of(x).ProtoReflect()
+}
+
+// DebugBlackMagicProbeServerConfiguration is the server_configuration payload
+// when Server == "blackmagic".
+type DebugBlackMagicProbeServerConfiguration struct {
+	GdbPort        string   `protobuf:"bytes,1,opt,name=gdb_port,json=gdbPort,proto3" json:"gdb_port,omitempty"`
+	ScanCmd        string   `protobuf:"bytes,2,opt,name=scan_cmd,json=scanCmd,proto3" json:"scan_cmd,omitempty"`
+	AttachTarget   string   `protobuf:"bytes,3,opt,name=attach_target,json=attachTarget,proto3" json:"attach_target,omitempty"`
+	MonitorPreInit []string `protobuf:"bytes,4,rep,name=monitor_pre_init,json=monitorPreInit,proto3" json:"monitor_pre_init,omitempty"`
+}
+
+func (x *DebugBlackMagicProbeServerConfiguration) Reset() {
+	*x = DebugBlackMagicProbeServerConfiguration{}
+}
+func (x *DebugBlackMagicProbeServerConfiguration) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+func (*DebugBlackMagicProbeServerConfiguration) ProtoMessage() {}
+func (x *DebugBlackMagicProbeServerConfiguration) ProtoReflect() protoreflect.Message {
+	return protoadapt.MessageV2Of(x).ProtoReflect()
+}
+
+func (x *DebugBlackMagicProbeServerConfiguration) GetGdbPort() string {
+	if x != nil {
+		return x.GdbPort
+	}
+	return ""
+}
+func (x *DebugBlackMagicProbeServerConfiguration) GetScanCmd() string {
+	if x != nil {
+		return x.ScanCmd
+	}
+	return ""
+}
+func (x *DebugBlackMagicProbeServerConfiguration) GetAttachTarget() string {
+	if x != nil {
+		return x.AttachTarget
+	}
+	return ""
+}
+func (x *DebugBlackMagicProbeServerConfiguration) GetMonitorPreInit() []string {
+	if x != nil {
+		return x.MonitorPreInit
+	}
+	return nil
+}
+
+var (
+	_ proto.Message = (*GetDebugConfigRequest)(nil)
+	_ proto.Message = (*GetDebugConfigResponse)(nil)
+	_ proto.Message = (*DebugOpenOCDServerConfiguration)(nil)
+	_ proto.Message = (*DebugBlackMagicProbeServerConfiguration)(nil)
+)